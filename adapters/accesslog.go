@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// clfTimeFormat is the timestamp layout used by the Apache Common/Combined
+// Log Format.
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog implements middlelogger.RequestLogger, formatting each request in
+// the Apache Common Log Format (or Combined Log Format, if NewAccessLog was
+// given combined=true), for compatibility with existing log-aggregation
+// pipelines that expect that format.
+type AccessLog struct {
+	mtx      sync.Mutex
+	w        io.Writer
+	combined bool
+}
+
+// NewAccessLog returns an AccessLog that writes to w, one line per request.
+// When combined is true, lines also include the Referer and User-Agent
+// headers (Combined Log Format); otherwise they follow the plain Common Log
+// Format.
+func NewAccessLog(w io.Writer, combined bool) *AccessLog {
+	return &AccessLog{w: w, combined: combined}
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (a *AccessLog) LogRequest(ld middlelogger.LogData) {
+	host := ld.R.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	line := fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %d`,
+		host,
+		ld.Start.Format(clfTimeFormat),
+		ld.R.Method,
+		ld.R.RequestURI,
+		ld.R.Proto,
+		ld.Status,
+		ld.BytesWritten,
+	)
+	if a.combined {
+		line += fmt.Sprintf(` %q %q`, ld.R.Referer(), ld.R.UserAgent())
+	}
+
+	a.mtx.Lock()
+	fmt.Fprintln(a.w, line)
+	a.mtx.Unlock()
+}
@@ -0,0 +1,42 @@
+package adapters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestAccessLogCommonFormat tests that AccessLog renders the Common Log
+// Format when combined is false.
+func TestAccessLogCommonFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logg := NewAccessLog(&buf, false)
+	logg.LogRequest(newTestLogData())
+
+	line := buf.String()
+	if !strings.Contains(line, `"GET /foo?bar=1 HTTP/1.1"`) {
+		t.Fatalf("unexpected request line: %s", line)
+	}
+	if !strings.Contains(line, " 200 42") {
+		t.Fatalf("unexpected status/bytes: %s", line)
+	}
+	if strings.Contains(line, "test-agent") {
+		t.Fatalf("common format should not include user agent: %s", line)
+	}
+}
+
+// TestAccessLogCombinedFormat tests that AccessLog appends referer and
+// user-agent when combined is true.
+func TestAccessLogCombinedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logg := NewAccessLog(&buf, true)
+	logg.LogRequest(newTestLogData())
+
+	line := buf.String()
+	if !strings.Contains(line, `"http://example.com"`) {
+		t.Fatalf("unexpected referer: %s", line)
+	}
+	if !strings.Contains(line, `"test-agent"`) {
+		t.Fatalf("unexpected user agent: %s", line)
+	}
+}
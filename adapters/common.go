@@ -0,0 +1,68 @@
+// Package adapters provides ready-to-use middlelogger.RequestLogger
+// implementations backed by popular structured logging libraries, plus an
+// Apache Common/Combined Log Format logger for compatibility with existing
+// log-aggregation pipelines.
+package adapters
+
+import (
+	"time"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// defaultRequestIDHeader is the header inspected for a request id when none
+// is configured via WithRequestIDHeader.
+const defaultRequestIDHeader = "X-Request-Id"
+
+// config holds the options shared by every adapter in this package.
+type config struct {
+	requestIDHeader string
+}
+
+func newConfig(opts ...Option) *config {
+	c := &config{requestIDHeader: defaultRequestIDHeader}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Option configures optional behavior shared across the adapters in this
+// package.
+type Option func(*config)
+
+// WithRequestIDHeader overrides the request header inspected for a request
+// id to attach to log lines. The default is "X-Request-Id".
+func WithRequestIDHeader(header string) Option {
+	return func(c *config) {
+		c.requestIDHeader = header
+	}
+}
+
+// commonFields are the scalar fields every adapter emits for a request,
+// extracted from a middlelogger.LogData.
+type commonFields struct {
+	Method     string
+	URI        string
+	Status     int
+	DurationMs float64
+	Bytes      int64
+	RemoteAddr string
+	UserAgent  string
+	Referer    string
+	RequestID  string
+}
+
+func extractCommon(ld middlelogger.LogData, requestIDHeader string) commonFields {
+	return commonFields{
+		Method:     ld.R.Method,
+		URI:        ld.R.RequestURI,
+		Status:     ld.Status,
+		DurationMs: float64(ld.TotalTime) / float64(time.Millisecond),
+		Bytes:      ld.BytesWritten,
+		RemoteAddr: ld.R.RemoteAddr,
+		UserAgent:  ld.R.UserAgent(),
+		Referer:    ld.R.Referer(),
+		RequestID:  ld.R.Header.Get(requestIDHeader),
+	}
+}
@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// wantKeys are the fields every adapter is documented to emit for a
+// request that carries a request id and an Extra entry.
+var wantKeys = []string{
+	"method", "uri", "status", "duration_ms", "bytes",
+	"remote_addr", "user_agent", "referer", "request_id", "user",
+}
+
+// envelopeKeys are fields the underlying logging libraries add on their own
+// (timestamp, level, message) and that are irrelevant to what the adapters
+// themselves contribute, so they're excluded before comparing key sets.
+var envelopeKeys = map[string]bool{
+	"time": true, "level": true, "msg": true, "message": true,
+}
+
+// assertExactKeys fails the test unless got contains exactly want, ignoring
+// envelopeKeys.
+func assertExactKeys(t *testing.T, got map[string]interface{}, want []string) {
+	t.Helper()
+
+	gotKeys := make([]string, 0, len(got))
+	for k := range got {
+		if envelopeKeys[k] {
+			continue
+		}
+		gotKeys = append(gotKeys, k)
+	}
+	sort.Strings(gotKeys)
+
+	wantSorted := append([]string(nil), want...)
+	sort.Strings(wantSorted)
+
+	if !reflect.DeepEqual(gotKeys, wantSorted) {
+		t.Errorf("unexpected key set.\nwant=%v\ngot=%v", wantSorted, gotKeys)
+	}
+}
+
+// TestAdapterKeySet tests, for every adapter in this package, that
+// LogRequest emits exactly the documented set of keys, and that
+// WithRequestIDHeader is honored when overriding the default header.
+func TestAdapterKeySet(t *testing.T) {
+	adapters := []struct {
+		name string
+		log  func(ld middlelogger.LogData, opts ...Option) map[string]interface{}
+	}{
+		{"slog", logWithSlog},
+		{"zap", logWithZap},
+		{"zerolog", logWithZerolog},
+	}
+
+	for _, a := range adapters {
+		t.Run(a.name, func(t *testing.T) {
+			got := a.log(newTestLogData())
+			assertExactKeys(t, got, wantKeys)
+		})
+
+		t.Run(a.name+"/custom request id header", func(t *testing.T) {
+			ld := newTestLogData()
+			ld.R.Header.Del("X-Request-Id")
+			ld.R.Header.Set("X-Trace-Id", "trace-456")
+
+			got := a.log(ld, WithRequestIDHeader("X-Trace-Id"))
+			assertExactKeys(t, got, wantKeys)
+
+			if got["request_id"] != "trace-456" {
+				t.Errorf("unexpected request_id. want=%q got=%v", "trace-456", got["request_id"])
+			}
+		})
+	}
+}
+
+func logWithSlog(ld middlelogger.LogData, opts ...Option) map[string]interface{} {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	NewSlogAdapter(logger, opts...).LogRequest(ld)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		panic(err)
+	}
+	return got
+}
+
+func logWithZap(ld middlelogger.LogData, opts ...Option) map[string]interface{} {
+	core, logs := observer.New(zap.InfoLevel)
+	NewZapAdapter(zap.New(core), opts...).LogRequest(ld)
+	return logs.All()[0].ContextMap()
+}
+
+func logWithZerolog(ld middlelogger.LogData, opts ...Option) map[string]interface{} {
+	var buf bytes.Buffer
+	NewZerologAdapter(zerolog.New(&buf), opts...).LogRequest(ld)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		panic(err)
+	}
+	return got
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// SlogAdapter implements middlelogger.RequestLogger, middlelogger.PanicLogger
+// and middlelogger.SlowRequestLogger on top of a *slog.Logger, with a fixed
+// cutoff time of 1 second for slow requests.
+type SlogAdapter struct {
+	logger *slog.Logger
+	cfg    *config
+}
+
+// NewSlogAdapter returns a SlogAdapter that logs to logger.
+func NewSlogAdapter(logger *slog.Logger, opts ...Option) *SlogAdapter {
+	return &SlogAdapter{logger: logger, cfg: newConfig(opts...)}
+}
+
+func (a *SlogAdapter) attrs(ld middlelogger.LogData) []any {
+	c := extractCommon(ld, a.cfg.requestIDHeader)
+	attrs := []any{
+		slog.String("method", c.Method),
+		slog.String("uri", c.URI),
+		slog.Int("status", c.Status),
+		slog.Float64("duration_ms", c.DurationMs),
+		slog.Int64("bytes", c.Bytes),
+		slog.String("remote_addr", c.RemoteAddr),
+		slog.String("user_agent", c.UserAgent),
+		slog.String("referer", c.Referer),
+	}
+	if c.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", c.RequestID))
+	}
+	for k, v := range ld.Extra {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	return attrs
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (a *SlogAdapter) LogRequest(ld middlelogger.LogData) {
+	a.logger.Info("request", a.attrs(ld)...)
+}
+
+// LogPanic is part of the middlelogger.PanicLogger interface.
+func (a *SlogAdapter) LogPanic(ld middlelogger.LogData, err interface{}) {
+	attrs := append(a.attrs(ld), slog.Any("panic", err))
+	if len(ld.Stack) > 0 {
+		attrs = append(attrs, slog.String("stack", string(ld.Stack)))
+	}
+	a.logger.Error("panic", attrs...)
+}
+
+func (a *SlogAdapter) Cutoff(*http.Request) time.Duration {
+	return time.Second
+}
+
+func (a *SlogAdapter) MultipleLogs(*http.Request) bool {
+	return true
+}
+
+// LogSlowRequest is part of the middlelogger.SlowRequestLogger interface.
+func (a *SlogAdapter) LogSlowRequest(ld middlelogger.LogData, i int) {
+	attrs := append(a.attrs(ld), slog.Int("tick", i))
+	a.logger.Warn("slow request", attrs...)
+}
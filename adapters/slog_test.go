@@ -0,0 +1,22 @@
+package adapters
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/matheusd/middlelogger"
+)
+
+func newTestLogData() middlelogger.LogData {
+	r := httptest.NewRequest("GET", "/foo?bar=1", nil)
+	r.Header.Set("User-Agent", "test-agent")
+	r.Header.Set("Referer", "http://example.com")
+	r.Header.Set("X-Request-Id", "req-123")
+	return middlelogger.LogData{
+		R:            r,
+		Status:       200,
+		TotalTime:    15 * time.Millisecond,
+		BytesWritten: 42,
+		Extra:        map[string]interface{}{"user": "alice"},
+	}
+}
@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// ZapAdapter implements middlelogger.RequestLogger, middlelogger.PanicLogger
+// and middlelogger.SlowRequestLogger on top of a *zap.Logger, with a fixed
+// cutoff time of 1 second for slow requests.
+type ZapAdapter struct {
+	logger *zap.Logger
+	cfg    *config
+}
+
+// NewZapAdapter returns a ZapAdapter that logs to logger.
+func NewZapAdapter(logger *zap.Logger, opts ...Option) *ZapAdapter {
+	return &ZapAdapter{logger: logger, cfg: newConfig(opts...)}
+}
+
+func (a *ZapAdapter) fields(ld middlelogger.LogData) []zap.Field {
+	c := extractCommon(ld, a.cfg.requestIDHeader)
+	fields := []zap.Field{
+		zap.String("method", c.Method),
+		zap.String("uri", c.URI),
+		zap.Int("status", c.Status),
+		zap.Float64("duration_ms", c.DurationMs),
+		zap.Int64("bytes", c.Bytes),
+		zap.String("remote_addr", c.RemoteAddr),
+		zap.String("user_agent", c.UserAgent),
+		zap.String("referer", c.Referer),
+	}
+	if c.RequestID != "" {
+		fields = append(fields, zap.String("request_id", c.RequestID))
+	}
+	for k, v := range ld.Extra {
+		fields = append(fields, zap.Any(k, v))
+	}
+	return fields
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (a *ZapAdapter) LogRequest(ld middlelogger.LogData) {
+	a.logger.Info("request", a.fields(ld)...)
+}
+
+// LogPanic is part of the middlelogger.PanicLogger interface.
+func (a *ZapAdapter) LogPanic(ld middlelogger.LogData, err interface{}) {
+	fields := append(a.fields(ld), zap.Any("panic", err))
+	if len(ld.Stack) > 0 {
+		fields = append(fields, zap.ByteString("stack", ld.Stack))
+	}
+	a.logger.Error("panic", fields...)
+}
+
+func (a *ZapAdapter) Cutoff(*http.Request) time.Duration {
+	return time.Second
+}
+
+func (a *ZapAdapter) MultipleLogs(*http.Request) bool {
+	return true
+}
+
+// LogSlowRequest is part of the middlelogger.SlowRequestLogger interface.
+func (a *ZapAdapter) LogSlowRequest(ld middlelogger.LogData, i int) {
+	fields := append(a.fields(ld), zap.Int("tick", i))
+	a.logger.Warn("slow request", fields...)
+}
@@ -0,0 +1,26 @@
+package adapters
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestZapAdapterLogsRequest is a smoke test that LogRequest reaches the
+// underlying *zap.Logger. See TestAdapterKeySet for the exact-key-set
+// assertion shared across adapters.
+func TestZapAdapterLogsRequest(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	adapter := NewZapAdapter(zap.New(core))
+
+	adapter.LogRequest(newTestLogData())
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("unexpected nb of log entries. want=1 got=%d", len(entries))
+	}
+	if entries[0].Message != "request" {
+		t.Errorf("unexpected log message. want=%q got=%q", "request", entries[0].Message)
+	}
+}
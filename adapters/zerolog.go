@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// ZerologAdapter implements middlelogger.RequestLogger,
+// middlelogger.PanicLogger and middlelogger.SlowRequestLogger on top of a
+// zerolog.Logger, with a fixed cutoff time of 1 second for slow requests.
+type ZerologAdapter struct {
+	logger zerolog.Logger
+	cfg    *config
+}
+
+// NewZerologAdapter returns a ZerologAdapter that logs to logger.
+func NewZerologAdapter(logger zerolog.Logger, opts ...Option) *ZerologAdapter {
+	return &ZerologAdapter{logger: logger, cfg: newConfig(opts...)}
+}
+
+func (a *ZerologAdapter) populate(e *zerolog.Event, ld middlelogger.LogData) *zerolog.Event {
+	c := extractCommon(ld, a.cfg.requestIDHeader)
+	e = e.Str("method", c.Method).
+		Str("uri", c.URI).
+		Int("status", c.Status).
+		Float64("duration_ms", c.DurationMs).
+		Int64("bytes", c.Bytes).
+		Str("remote_addr", c.RemoteAddr).
+		Str("user_agent", c.UserAgent).
+		Str("referer", c.Referer)
+	if c.RequestID != "" {
+		e = e.Str("request_id", c.RequestID)
+	}
+	for k, v := range ld.Extra {
+		e = e.Interface(k, v)
+	}
+	return e
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (a *ZerologAdapter) LogRequest(ld middlelogger.LogData) {
+	a.populate(a.logger.Info(), ld).Msg("request")
+}
+
+// LogPanic is part of the middlelogger.PanicLogger interface.
+func (a *ZerologAdapter) LogPanic(ld middlelogger.LogData, err interface{}) {
+	e := a.populate(a.logger.Error(), ld).Interface("panic", err)
+	if len(ld.Stack) > 0 {
+		e = e.Bytes("stack", ld.Stack)
+	}
+	e.Msg("panic")
+}
+
+func (a *ZerologAdapter) Cutoff(*http.Request) time.Duration {
+	return time.Second
+}
+
+func (a *ZerologAdapter) MultipleLogs(*http.Request) bool {
+	return true
+}
+
+// LogSlowRequest is part of the middlelogger.SlowRequestLogger interface.
+func (a *ZerologAdapter) LogSlowRequest(ld middlelogger.LogData, i int) {
+	a.populate(a.logger.Warn(), ld).Int("tick", i).Msg("slow request")
+}
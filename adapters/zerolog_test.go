@@ -0,0 +1,27 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// TestZerologAdapterLogsRequest is a smoke test that LogRequest reaches the
+// underlying zerolog.Logger. See TestAdapterKeySet for the exact-key-set
+// assertion shared across adapters.
+func TestZerologAdapterLogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewZerologAdapter(zerolog.New(&buf))
+
+	adapter.LogRequest(newTestLogData())
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if got["message"] != "request" {
+		t.Errorf("unexpected message field. want=%q got=%v", "request", got["message"])
+	}
+}
@@ -0,0 +1,87 @@
+package middlelogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// contextKey is an unexported type used for the keys stored in a request's
+// context.Context, to avoid clashes with other packages.
+type contextKey int
+
+// requestContextKey is the key under which the *RequestContext for a request
+// is stored in its context.Context.
+const requestContextKey contextKey = iota
+
+// RequestContext allows a handler wrapped by the logging middleware to
+// annotate the request's eventual log line with structured fields and
+// free-form messages. It is safe for concurrent use.
+type RequestContext struct {
+	mtx      sync.Mutex
+	extra    map[string]interface{}
+	addendum []string
+}
+
+// Addf appends a formatted message to the request's log addendum.
+func (rc *RequestContext) Addf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+
+	rc.mtx.Lock()
+	rc.addendum = append(rc.addendum, msg)
+	rc.mtx.Unlock()
+}
+
+// Set attaches a key/value field to the request's log line.
+func (rc *RequestContext) Set(key string, val interface{}) {
+	rc.mtx.Lock()
+	if rc.extra == nil {
+		rc.extra = make(map[string]interface{})
+	}
+	rc.extra[key] = val
+	rc.mtx.Unlock()
+}
+
+// snapshot returns copies of the current extra fields and addendum, safe to
+// hand off to a logger that might run concurrently with further Addf/Set
+// calls (e.g. from the slow request logger goroutine).
+func (rc *RequestContext) snapshot() (map[string]interface{}, []string) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+
+	var extra map[string]interface{}
+	if len(rc.extra) > 0 {
+		extra = make(map[string]interface{}, len(rc.extra))
+		for k, v := range rc.extra {
+			extra[k] = v
+		}
+	}
+
+	var addendum []string
+	if len(rc.addendum) > 0 {
+		addendum = make([]string, len(rc.addendum))
+		copy(addendum, rc.addendum)
+	}
+
+	return extra, addendum
+}
+
+// withRequestContext returns a copy of r whose context.Context carries rc, so
+// that LogOf(r) can later retrieve it.
+func withRequestContext(r *http.Request, rc *RequestContext) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestContextKey, rc))
+}
+
+// LogOf returns the RequestContext associated with r, so that a handler
+// wrapped by LoggerMiddleware can annotate the request's eventual log line.
+//
+// It always returns a usable RequestContext, even when r was not obtained
+// from a handler wrapped by this package, so callers don't need to nil-check
+// the result.
+func LogOf(r *http.Request) *RequestContext {
+	if rc, ok := r.Context().Value(requestContextKey).(*RequestContext); ok {
+		return rc
+	}
+	return &RequestContext{}
+}
@@ -0,0 +1,47 @@
+package middlelogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestLogOfAnnotatesLogData tests that fields and messages attached via
+// LogOf(r) during the handler's execution show up on the logged LogData.
+func TestLogOfAnnotatesLogData(t *testing.T) {
+	logg := &mockLogger{}
+	middle := LoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		LogOf(r).Set("user", "alice")
+		LogOf(r).Addf("processed %d items", 3)
+		w.WriteHeader(200)
+	}), logg)
+
+	r := httptest.NewRequest("", "/", nil)
+	w := httptest.NewRecorder()
+	middle.ServeHTTP(w, r)
+
+	if len(logg.reqs) != 1 {
+		t.Fatalf("unexpected nb of logged requests. want=1 got=%d", len(logg.reqs))
+	}
+	ld := logg.reqs[0]
+	if ld.Extra["user"] != "alice" {
+		t.Fatalf("unexpected Extra[\"user\"]. want=alice got=%v", ld.Extra["user"])
+	}
+	if len(ld.Addendum) != 1 || ld.Addendum[0] != "processed 3 items" {
+		t.Fatalf("unexpected Addendum. got=%v", ld.Addendum)
+	}
+}
+
+// TestLogOfWithoutMiddleware tests that LogOf never returns nil, even for a
+// request that wasn't served through LoggerMiddleware.
+func TestLogOfWithoutMiddleware(t *testing.T) {
+	r := httptest.NewRequest("", "/", nil)
+	rc := LogOf(r)
+	if rc == nil {
+		t.Fatal("LogOf should never return nil")
+	}
+
+	// Addf/Set should be safe to call even though nothing will read them.
+	rc.Set("foo", "bar")
+	rc.Addf("hi")
+}
@@ -2,6 +2,7 @@ package middlelogger
 
 import (
 	"net/http"
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -15,6 +16,30 @@ type LogData struct {
 	Start        time.Time
 	TotalTime    time.Duration
 	BytesWritten int64
+
+	// Hijacked is true when the underlying connection was taken over via
+	// http.Hijacker before this LogData was produced, in which case Status
+	// and BytesWritten only reflect what was written before the hijack.
+	Hijacked bool
+
+	// Extra holds the key/value fields attached by the handler via
+	// LogOf(r).Set, if any.
+	Extra map[string]interface{}
+
+	// Addendum holds the free-form messages attached by the handler via
+	// LogOf(r).Addf, if any.
+	Addendum []string
+
+	// Stack holds the stack trace captured at the time of logging, when the
+	// configured StacktracePred returned true for Status.
+	Stack []byte
+
+	// FlushCount is the number of times the handler called Flush() on the
+	// response, via the optional http.Flusher interface.
+	FlushCount int
+
+	// LastFlush is the time of the most recent Flush() call, if any.
+	LastFlush time.Time
 }
 
 // RequestLogger defines the interface that custom loggers need to offer.
@@ -36,17 +61,46 @@ type SlowRequestLogger interface {
 	LogSlowRequest(LogData, int)
 }
 
+// StacktracePred decides, based on a response's status code, whether a stack
+// trace should be captured and attached to the LogData passed to LogRequest
+// or LogPanic.
+type StacktracePred func(status int) bool
+
+// defaultStacktracePred only requests a stack trace for server errors.
+func defaultStacktracePred(status int) bool {
+	return status >= http.StatusInternalServerError
+}
+
+// requestSnapshot is a point-in-time copy of the mutable state tracked by a
+// loggedRequest, safe to read after the lock has been released.
+type requestSnapshot struct {
+	status       int
+	bytesWritten int64
+	wroteHeader  bool
+	hijacked     bool
+	flushCount   int
+	lastFlush    time.Time
+}
+
 // loggedRequest maintains the state about a request that should be logged. It
 // implements http.ResponseWriter so that the status code written to the client
 // and any bytes sent can be accounted for.
 type loggedRequest struct {
 	w http.ResponseWriter
 
+	// hijackChan is closed the first time Hijack() succeeds, so that a
+	// slowLog goroutine waiting on it can emit a terminal log entry right
+	// away instead of on its next tick.
+	hijackChan chan struct{}
+
 	// mtx protects the following fields.
 	mtx          sync.Mutex
 	status       int
 	wroteHeader  bool
 	bytesWritten int64
+	hijacked     bool
+	flushCount   int
+	lastFlush    time.Time
 }
 
 func (lr *loggedRequest) WriteHeader(code int) {
@@ -75,24 +129,76 @@ func (lr *loggedRequest) Write(data []byte) (int, error) {
 	return written, err
 }
 
-func (lr *loggedRequest) currentData() (int, int64) {
+func (lr *loggedRequest) snapshot() requestSnapshot {
 	lr.mtx.Lock()
 	defer lr.mtx.Unlock()
-	return lr.status, lr.bytesWritten
+	return requestSnapshot{
+		status:       lr.status,
+		bytesWritten: lr.bytesWritten,
+		wroteHeader:  lr.wroteHeader,
+		hijacked:     lr.hijacked,
+		flushCount:   lr.flushCount,
+		lastFlush:    lr.lastFlush,
+	}
+}
+
+// markHijacked records that the underlying connection was taken over via
+// Hijack(), and closes hijackChan the first time it is called so that a
+// slowLog goroutine waiting on it wakes up immediately.
+func (lr *loggedRequest) markHijacked() {
+	lr.mtx.Lock()
+	already := lr.hijacked
+	lr.hijacked = true
+	lr.mtx.Unlock()
+
+	if !already {
+		close(lr.hijackChan)
+	}
+}
+
+// recordFlush accounts for a call to Flush() on the response.
+func (lr *loggedRequest) recordFlush() {
+	lr.mtx.Lock()
+	lr.flushCount++
+	lr.lastFlush = time.Now()
+	lr.mtx.Unlock()
 }
 
 type logHandler struct {
-	logger      RequestLogger
-	panicLogger PanicLogger
-	slowLogger  SlowRequestLogger
-	next        http.Handler
+	logger         RequestLogger
+	panicLogger    PanicLogger
+	slowLogger     SlowRequestLogger
+	next           http.Handler
+	stacktracePred StacktracePred
+	panicStatus    int
+}
+
+// Option configures optional behavior of the middleware constructed by
+// NewLoggerMiddleware.
+type Option func(*logHandler)
+
+// WithStacktracePred overrides which response statuses get a stack trace
+// attached to their LogData. The default only does so for 5xx statuses.
+func WithStacktracePred(pred StacktracePred) Option {
+	return func(lh *logHandler) {
+		lh.stacktracePred = pred
+	}
+}
+
+// WithPanicStatus overrides the status code written to the client when a
+// panic is recovered before anything has been written to the response. The
+// default is http.StatusInternalServerError.
+func WithPanicStatus(status int) Option {
+	return func(lh *logHandler) {
+		lh.panicStatus = status
+	}
 }
 
 // slowLog logs slow requests. It MUST be called as a goroutine and expects
 // slowLogger to be filled.
 func (lh *logHandler) slowLog(cutoff time.Duration, multiple bool,
 	doneChan chan struct{}, w http.ResponseWriter, r *http.Request,
-	start time.Time, lr *loggedRequest) {
+	start time.Time, lr *loggedRequest, rc *RequestContext) {
 
 	for i := 0; multiple || i == 0; i++ {
 		select {
@@ -100,25 +206,50 @@ func (lh *logHandler) slowLog(cutoff time.Duration, multiple bool,
 			// Done, so no more logging needed.
 			return
 
+		case <-lr.hijackChan:
+			// The connection was upgraded, e.g. to a WebSocket or
+			// SSE stream: bytesWritten no longer reflects reality
+			// and this ticker would otherwise fire forever, so emit
+			// one terminal log entry and stop.
+			ld := lh.logDataFor(r, w, start, lr, rc)
+			lh.slowLogger.LogSlowRequest(ld, i)
+			return
+
 		case <-time.After(cutoff):
-			status, bytesWritten := lr.currentData()
-			ld := LogData{
-				R:            r,
-				W:            w,
-				Start:        start,
-				TotalTime:    time.Since(start),
-				Status:       status,
-				BytesWritten: bytesWritten,
-			}
+			ld := lh.logDataFor(r, w, start, lr, rc)
 			lh.slowLogger.LogSlowRequest(ld, i)
 		}
 	}
 }
 
+// logDataFor builds the LogData for the current state of lr and rc.
+func (lh *logHandler) logDataFor(r *http.Request, w http.ResponseWriter,
+	start time.Time, lr *loggedRequest, rc *RequestContext) LogData {
+
+	snap := lr.snapshot()
+	extra, addendum := rc.snapshot()
+	return LogData{
+		R:            r,
+		W:            w,
+		Start:        start,
+		TotalTime:    time.Since(start),
+		Status:       snap.status,
+		BytesWritten: snap.bytesWritten,
+		Hijacked:     snap.hijacked,
+		Extra:        extra,
+		Addendum:     addendum,
+		FlushCount:   snap.flushCount,
+		LastFlush:    snap.lastFlush,
+	}
+}
+
 func (lh *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	lr := &loggedRequest{w: w}
+	lr := &loggedRequest{w: w, hijackChan: make(chan struct{})}
 	start := time.Now()
 
+	rc := &RequestContext{}
+	r = withRequestContext(r, rc)
+
 	// Log slow requests if commanded to, so that we don't miss out some
 	// log messages.
 	var doneChan chan struct{}
@@ -127,7 +258,7 @@ func (lh *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		multiple := lh.slowLogger.MultipleLogs(r)
 		if cutoff > 0 {
 			doneChan = make(chan struct{})
-			go lh.slowLog(cutoff, multiple, doneChan, w, r, start, lr)
+			go lh.slowLog(cutoff, multiple, doneChan, w, r, start, lr, rc)
 		}
 	}
 
@@ -138,15 +269,8 @@ func (lh *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			close(doneChan)
 		}
 
-		status, bytesWritten := lr.currentData()
-		ld := LogData{
-			R:            r,
-			W:            w,
-			Start:        start,
-			TotalTime:    time.Since(start),
-			Status:       status,
-			BytesWritten: bytesWritten,
-		}
+		snap := lr.snapshot()
+		ld := lh.logDataFor(r, w, start, lr, rc)
 
 		// We _only_ attempt to recover from panics if a
 		// panicLogger was specified, otherwise we might forbid
@@ -154,15 +278,33 @@ func (lh *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// panic.
 		if lh.panicLogger != nil {
 			if err := recover(); err != nil {
+				// Avoid leaking a half-written body: if the
+				// handler panicked before writing anything,
+				// respond with the configured error status. A
+				// hijacked connection is no longer ours to
+				// write to, so leave it alone.
+				if !snap.wroteHeader && !snap.hijacked {
+					http.Error(lr, http.StatusText(lh.panicStatus),
+						lh.panicStatus)
+					newSnap := lr.snapshot()
+					ld.Status = newSnap.status
+					ld.BytesWritten = newSnap.bytesWritten
+				}
+				if lh.stacktracePred(ld.Status) {
+					ld.Stack = debug.Stack()
+				}
 				lh.panicLogger.LogPanic(ld, err)
 				return
 			}
 		}
 
+		if lh.stacktracePred(ld.Status) {
+			ld.Stack = debug.Stack()
+		}
 		lh.logger.LogRequest(ld)
 	}()
 
-	lh.next.ServeHTTP(lr, r)
+	lh.next.ServeHTTP(wrapResponseWriter(lr, w), r)
 }
 
 // LoggerMiddleware is a middleware that provides callers with the ability to
@@ -174,13 +316,26 @@ func (lh *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // If logger also implements PanicLogger, then any panics that occur during the
 // call to the next handler are recovered from and logged appropriately.
 func LoggerMiddleware(next http.Handler, logger RequestLogger) http.Handler {
+	return NewLoggerMiddleware(next, logger)
+}
+
+// NewLoggerMiddleware is like LoggerMiddleware, but accepts a variadic list
+// of Options to further configure the middleware's behavior.
+func NewLoggerMiddleware(next http.Handler, logger RequestLogger, opts ...Option) http.Handler {
 	panicLogger, _ := logger.(PanicLogger)
 	slowLogger, _ := logger.(SlowRequestLogger)
 
-	return &logHandler{
-		logger:      logger,
-		panicLogger: panicLogger,
-		slowLogger:  slowLogger,
-		next:        next,
+	lh := &logHandler{
+		logger:         logger,
+		panicLogger:    panicLogger,
+		slowLogger:     slowLogger,
+		next:           next,
+		stacktracePred: defaultStacktracePred,
+		panicStatus:    http.StatusInternalServerError,
+	}
+	for _, opt := range opts {
+		opt(lh)
 	}
+
+	return lh
 }
@@ -67,6 +67,12 @@ func panicHandler(status int) http.Handler {
 	})
 }
 
+func panicBeforeWriteHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boo!")
+	})
+}
+
 func slowHandler(status int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var data [8]byte
@@ -125,6 +131,61 @@ func TestLogsPanic(t *testing.T) {
 
 }
 
+// TestPanicBeforeWriteRespondsWithDefaultError tests that a panic that
+// happens before anything was written to the response is turned into the
+// configured default error response, instead of leaking an empty body.
+func TestPanicBeforeWriteRespondsWithDefaultError(t *testing.T) {
+	logg := &mockLogger{}
+	middle := LoggerMiddleware(panicBeforeWriteHandler(), logg)
+	r := httptest.NewRequest("", "/", nil)
+	w := httptest.NewRecorder()
+	middle.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected response status. want=%d got=%d",
+			http.StatusInternalServerError, w.Code)
+	}
+	if len(logg.panics) != 1 {
+		t.Fatalf("unexpected nb of logged panics. want=1 got=%d",
+			len(logg.panics))
+	}
+	if logg.panics[0].Status != http.StatusInternalServerError {
+		t.Fatalf("unexpected logged status. want=%d got=%d",
+			http.StatusInternalServerError, logg.panics[0].Status)
+	}
+}
+
+// TestStacktracePred tests that a stack trace is only attached to LogData
+// when the configured StacktracePred returns true for the response's status.
+func TestStacktracePred(t *testing.T) {
+	logg := &mockLogger{}
+	middle := NewLoggerMiddleware(mockHandler(200), logg,
+		WithStacktracePred(func(status int) bool { return status >= 500 }))
+	r := httptest.NewRequest("", "/", nil)
+	w := httptest.NewRecorder()
+	middle.ServeHTTP(w, r)
+
+	if len(logg.reqs) != 1 {
+		t.Fatalf("unexpected nb of logged requests. want=1 got=%d", len(logg.reqs))
+	}
+	if logg.reqs[0].Stack != nil {
+		t.Fatal("stack trace should not be attached for a 200 status")
+	}
+
+	logg = &mockLogger{}
+	middle = NewLoggerMiddleware(mockHandler(500), logg,
+		WithStacktracePred(func(status int) bool { return status >= 500 }))
+	w = httptest.NewRecorder()
+	middle.ServeHTTP(w, r)
+
+	if len(logg.reqs) != 1 {
+		t.Fatalf("unexpected nb of logged requests. want=1 got=%d", len(logg.reqs))
+	}
+	if logg.reqs[0].Stack == nil {
+		t.Fatal("stack trace should be attached for a 500 status")
+	}
+}
+
 // TestLogsSlow tests that the logger middleware correctly logs slow requests.
 func TestLogsSlow(t *testing.T) {
 	logg := &mockLogger{cutoff: time.Millisecond * 5, multiple: true}
@@ -0,0 +1,83 @@
+// Package sampling provides RequestLogger decorators that cut down on the
+// volume of logging performed by an inner middlelogger.RequestLogger, for use
+// on high-QPS endpoints.
+//
+// Every decorator in this package forwards PanicLogger and SlowRequestLogger
+// straight through to the wrapped logger when it implements them, so that
+// slow-request and panic events always reach it regardless of sampling.
+package sampling
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// wrapDecorator returns base, augmented with the PanicLogger and
+// SlowRequestLogger implementations of inner when inner provides them. This
+// mirrors the type-assertion trick LoggerMiddleware uses to decide which
+// optional interfaces a RequestLogger supports.
+func wrapDecorator(base middlelogger.RequestLogger, inner middlelogger.RequestLogger) middlelogger.RequestLogger {
+	panicLogger, hasPanic := inner.(middlelogger.PanicLogger)
+	slowLogger, hasSlow := inner.(middlelogger.SlowRequestLogger)
+
+	switch {
+	case hasPanic && hasSlow:
+		return &panicSlowLogger{base, panicLogger, slowLogger}
+	case hasPanic:
+		return &panicOnlyLogger{base, panicLogger}
+	case hasSlow:
+		return &slowOnlyLogger{base, slowLogger}
+	default:
+		return base
+	}
+}
+
+type panicOnlyLogger struct {
+	middlelogger.RequestLogger
+	panicLogger middlelogger.PanicLogger
+}
+
+func (l *panicOnlyLogger) LogPanic(ld middlelogger.LogData, err interface{}) {
+	l.panicLogger.LogPanic(ld, err)
+}
+
+type slowOnlyLogger struct {
+	middlelogger.RequestLogger
+	slowLogger middlelogger.SlowRequestLogger
+}
+
+func (l *slowOnlyLogger) Cutoff(r *http.Request) time.Duration {
+	return l.slowLogger.Cutoff(r)
+}
+
+func (l *slowOnlyLogger) MultipleLogs(r *http.Request) bool {
+	return l.slowLogger.MultipleLogs(r)
+}
+
+func (l *slowOnlyLogger) LogSlowRequest(ld middlelogger.LogData, i int) {
+	l.slowLogger.LogSlowRequest(ld, i)
+}
+
+type panicSlowLogger struct {
+	middlelogger.RequestLogger
+	panicLogger middlelogger.PanicLogger
+	slowLogger  middlelogger.SlowRequestLogger
+}
+
+func (l *panicSlowLogger) LogPanic(ld middlelogger.LogData, err interface{}) {
+	l.panicLogger.LogPanic(ld, err)
+}
+
+func (l *panicSlowLogger) Cutoff(r *http.Request) time.Duration {
+	return l.slowLogger.Cutoff(r)
+}
+
+func (l *panicSlowLogger) MultipleLogs(r *http.Request) bool {
+	return l.slowLogger.MultipleLogs(r)
+}
+
+func (l *panicSlowLogger) LogSlowRequest(ld middlelogger.LogData, i int) {
+	l.slowLogger.LogSlowRequest(ld, i)
+}
@@ -0,0 +1,40 @@
+package sampling
+
+import (
+	"math/rand"
+	"net/http"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// errorBiased samples 4xx/5xx responses at errorRate and every other
+// response at successRate.
+type errorBiased struct {
+	inner       middlelogger.RequestLogger
+	errorRate   float64
+	successRate float64
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (e *errorBiased) LogRequest(ld middlelogger.LogData) {
+	rate := e.successRate
+	if ld.Status >= http.StatusBadRequest {
+		rate = e.errorRate
+	}
+	if rand.Float64() < rate {
+		e.inner.LogRequest(ld)
+	}
+}
+
+// NewErrorBiased returns a RequestLogger that forwards 4xx/5xx responses to
+// inner at errorRate and every other response at successRate. Passing
+// errorRate as 1 logs every error. Panics and slow requests always bypass
+// sampling: if inner also implements middlelogger.PanicLogger or
+// middlelogger.SlowRequestLogger, the returned logger does too.
+func NewErrorBiased(inner middlelogger.RequestLogger, errorRate, successRate float64) middlelogger.RequestLogger {
+	return wrapDecorator(&errorBiased{
+		inner:       inner,
+		errorRate:   errorRate,
+		successRate: successRate,
+	}, inner)
+}
@@ -0,0 +1,86 @@
+package sampling
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// tokenBucket is a simple token bucket limited to qps tokens per second,
+// refilled continuously based on elapsed wall-clock time.
+type tokenBucket struct {
+	mtx    sync.Mutex
+	tokens float64
+	max    float64
+	last   time.Time
+}
+
+func newTokenBucket(qps int) *tokenBucket {
+	return &tokenBucket{
+		tokens: float64(qps),
+		max:    float64(qps),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.max
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// perRouteRateLimit logs at most qps requests per second for each key
+// returned by keyFn.
+type perRouteRateLimit struct {
+	inner middlelogger.RequestLogger
+	keyFn func(*http.Request) string
+	qps   int
+
+	mtx     sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (p *perRouteRateLimit) LogRequest(ld middlelogger.LogData) {
+	key := p.keyFn(ld.R)
+
+	p.mtx.Lock()
+	b, ok := p.buckets[key]
+	if !ok {
+		b = newTokenBucket(p.qps)
+		p.buckets[key] = b
+	}
+	p.mtx.Unlock()
+
+	if b.allow() {
+		p.inner.LogRequest(ld)
+	}
+}
+
+// NewPerRouteRateLimit returns a RequestLogger that forwards at most qps
+// requests per second to inner, for each distinct key returned by keyFn (e.g.
+// by route or by user). Panics and slow requests always bypass the limit: if
+// inner also implements middlelogger.PanicLogger or
+// middlelogger.SlowRequestLogger, the returned logger does too.
+func NewPerRouteRateLimit(inner middlelogger.RequestLogger, keyFn func(*http.Request) string, qps int) middlelogger.RequestLogger {
+	return wrapDecorator(&perRouteRateLimit{
+		inner:   inner,
+		keyFn:   keyFn,
+		qps:     qps,
+		buckets: make(map[string]*tokenBucket),
+	}, inner)
+}
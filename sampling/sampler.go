@@ -0,0 +1,28 @@
+package sampling
+
+import (
+	"math/rand"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// sampler logs a random fraction of requests.
+type sampler struct {
+	inner middlelogger.RequestLogger
+	rate  float64
+}
+
+// LogRequest is part of the middlelogger.RequestLogger interface.
+func (s *sampler) LogRequest(ld middlelogger.LogData) {
+	if rand.Float64() < s.rate {
+		s.inner.LogRequest(ld)
+	}
+}
+
+// NewSampler returns a RequestLogger that forwards a random fraction of
+// requests (0 <= rate <= 1) to inner. Panics and slow requests always bypass
+// sampling: if inner also implements middlelogger.PanicLogger or
+// middlelogger.SlowRequestLogger, the returned logger does too.
+func NewSampler(inner middlelogger.RequestLogger, rate float64) middlelogger.RequestLogger {
+	return wrapDecorator(&sampler{inner: inner, rate: rate}, inner)
+}
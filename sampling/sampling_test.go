@@ -0,0 +1,144 @@
+package sampling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matheusd/middlelogger"
+)
+
+// countingLogger counts how many times LogRequest was called.
+type countingLogger struct {
+	nb int
+}
+
+func (l *countingLogger) LogRequest(middlelogger.LogData) {
+	l.nb++
+}
+
+// fullLogger additionally implements PanicLogger and SlowRequestLogger, so
+// tests can verify those are always forwarded regardless of sampling.
+type fullLogger struct {
+	countingLogger
+	panics int
+	slows  int
+}
+
+func (l *fullLogger) LogPanic(middlelogger.LogData, interface{}) {
+	l.panics++
+}
+
+func (l *fullLogger) Cutoff(*http.Request) time.Duration {
+	return time.Second
+}
+
+func (l *fullLogger) MultipleLogs(*http.Request) bool {
+	return false
+}
+
+func (l *fullLogger) LogSlowRequest(middlelogger.LogData, int) {
+	l.slows++
+}
+
+func TestSamplerForwardsPanicAndSlow(t *testing.T) {
+	inner := &fullLogger{}
+	logg := NewSampler(inner, 0)
+
+	panicLogger, ok := logg.(middlelogger.PanicLogger)
+	if !ok {
+		t.Fatal("sampled logger should implement middlelogger.PanicLogger")
+	}
+	panicLogger.LogPanic(middlelogger.LogData{}, "boo")
+	if inner.panics != 1 {
+		t.Fatalf("unexpected nb of forwarded panics. want=1 got=%d", inner.panics)
+	}
+
+	slowLogger, ok := logg.(middlelogger.SlowRequestLogger)
+	if !ok {
+		t.Fatal("sampled logger should implement middlelogger.SlowRequestLogger")
+	}
+	slowLogger.LogSlowRequest(middlelogger.LogData{}, 0)
+	if inner.slows != 1 {
+		t.Fatalf("unexpected nb of forwarded slows. want=1 got=%d", inner.slows)
+	}
+}
+
+func TestSamplerRate(t *testing.T) {
+	inner := &countingLogger{}
+	logg := NewSampler(inner, 0)
+	for i := 0; i < 100; i++ {
+		logg.LogRequest(middlelogger.LogData{})
+	}
+	if inner.nb != 0 {
+		t.Fatalf("rate=0 should never forward. got=%d", inner.nb)
+	}
+
+	inner = &countingLogger{}
+	logg = NewSampler(inner, 1)
+	for i := 0; i < 100; i++ {
+		logg.LogRequest(middlelogger.LogData{})
+	}
+	if inner.nb != 100 {
+		t.Fatalf("rate=1 should always forward. got=%d", inner.nb)
+	}
+}
+
+func TestPerRouteRateLimit(t *testing.T) {
+	inner := &countingLogger{}
+	logg := NewPerRouteRateLimit(inner, func(r *http.Request) string {
+		return r.URL.Path
+	}, 2)
+
+	r := httptest.NewRequest("", "/foo", nil)
+	for i := 0; i < 5; i++ {
+		logg.LogRequest(middlelogger.LogData{R: r})
+	}
+	if inner.nb != 2 {
+		t.Fatalf("unexpected nb of forwarded requests. want=2 got=%d", inner.nb)
+	}
+
+	// A different key gets its own bucket.
+	other := httptest.NewRequest("", "/bar", nil)
+	logg.LogRequest(middlelogger.LogData{R: other})
+	if inner.nb != 3 {
+		t.Fatalf("unexpected nb of forwarded requests. want=3 got=%d", inner.nb)
+	}
+}
+
+func TestErrorBiased(t *testing.T) {
+	inner := &countingLogger{}
+	logg := NewErrorBiased(inner, 1, 0)
+
+	for i := 0; i < 10; i++ {
+		logg.LogRequest(middlelogger.LogData{Status: 200})
+	}
+	if inner.nb != 0 {
+		t.Fatalf("successRate=0 should never forward 2xx. got=%d", inner.nb)
+	}
+
+	for i := 0; i < 10; i++ {
+		logg.LogRequest(middlelogger.LogData{Status: 500})
+	}
+	if inner.nb != 10 {
+		t.Fatalf("errorRate=1 should always forward 5xx. got=%d", inner.nb)
+	}
+}
+
+// BenchmarkSampledOut benchmarks the sampled-out path, which should perform
+// no allocations.
+func BenchmarkSampledOut(b *testing.B) {
+	inner := &countingLogger{}
+	logg := NewSampler(inner, 0)
+	ld := middlelogger.LogData{}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logg.LogRequest(ld)
+	}
+	if inner.nb != 0 {
+		b.Fatalf("unexpected nb of forwarded requests. want=0 got=%d", inner.nb)
+	}
+}
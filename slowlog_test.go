@@ -0,0 +1,87 @@
+package middlelogger
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestSlowLogHijack tests that once the connection is hijacked, the slow
+// request logger emits a single terminal LogSlowRequest with Hijacked set,
+// instead of continuing to tick forever.
+func TestSlowLogHijack(t *testing.T) {
+	logg := &mockLogger{cutoff: time.Millisecond * 5, multiple: true}
+	releaseConn := make(chan struct{})
+
+	srv := httptest.NewServer(LoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("unexpected hijack error: %v", err)
+			return
+		}
+		defer conn.Close()
+		<-releaseConn
+	}), logg))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	// Give the handler time to hijack and the slow logger time to notice,
+	// then let the handler return so the server can shut down cleanly.
+	time.Sleep(time.Millisecond * 50)
+	close(releaseConn)
+
+	logg.mtx.Lock()
+	slow := append([]*LogData(nil), logg.slow...)
+	logg.mtx.Unlock()
+
+	if len(slow) != 1 {
+		t.Fatalf("unexpected nb of logged slows. want=1 got=%d", len(slow))
+	}
+	if !slow[0].Hijacked {
+		t.Fatal("terminal slow log entry should be marked as hijacked")
+	}
+}
+
+// TestLogsFlush tests that Flush() calls are accounted for on LogData.
+func TestLogsFlush(t *testing.T) {
+	logg := &mockLogger{}
+	middle := LoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.(http.Flusher).Flush()
+		w.(http.Flusher).Flush()
+	}), logg)
+
+	r := httptest.NewRequest("", "/", nil)
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	middle.ServeHTTP(w, r)
+
+	if len(logg.reqs) != 1 {
+		t.Fatalf("unexpected nb of logged requests. want=1 got=%d", len(logg.reqs))
+	}
+	if logg.reqs[0].FlushCount != 2 {
+		t.Fatalf("unexpected flush count. want=2 got=%d", logg.reqs[0].FlushCount)
+	}
+	if logg.reqs[0].LastFlush.IsZero() {
+		t.Fatal("LastFlush should be set")
+	}
+}
+
+// flushRecorder wraps an httptest.ResponseRecorder and replaces its Flush
+// method with a no-op, so the only Flush accounting in the test is the
+// middleware's own FlushCount and LastFlush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r *flushRecorder) Flush() {}
@@ -1,9 +1,11 @@
 package middlelogger
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 )
 
@@ -17,27 +19,36 @@ type StdLibLogger struct{}
 // LogRequest is part of the Logger interface.
 func (l StdLibLogger) LogRequest(ld LogData) {
 	log.Printf(
-		"%s %s %d %s %d",
+		"%s %s %d %s %d%s",
 		ld.R.Method,
 		ld.R.RequestURI,
 		ld.Status,
 		ld.TotalTime,
 		ld.BytesWritten,
+		formatExtra(ld),
 	)
+	if len(ld.Stack) > 0 {
+		log.Printf(string(ld.Stack))
+	}
 }
 
 // LogPanic is part of the PanicLogger interface.
 func (l StdLibLogger) LogPanic(ld LogData, err interface{}) {
 	log.Printf(
-		"%s %s %d %s %d (PANIC %v)",
+		"%s %s %d %s %d (PANIC %v)%s",
 		ld.R.Method,
 		ld.R.RequestURI,
 		ld.Status,
 		ld.TotalTime,
 		ld.BytesWritten,
 		err,
+		formatExtra(ld),
 	)
-	log.Printf(string(debug.Stack()))
+	if len(ld.Stack) > 0 {
+		log.Printf(string(ld.Stack))
+	} else {
+		log.Printf(string(debug.Stack()))
+	}
 }
 
 func (l StdLibLogger) Cutoff(*http.Request) time.Duration {
@@ -50,12 +61,26 @@ func (l StdLibLogger) MultipleLogs(*http.Request) bool {
 
 func (l StdLibLogger) LogSlowRequest(ld LogData, i int) {
 	log.Printf(
-		"%s %s %d %s %d (slow %d)",
+		"%s %s %d %s %d (slow %d)%s",
 		ld.R.Method,
 		ld.R.RequestURI,
 		ld.Status,
 		ld.TotalTime,
 		ld.BytesWritten,
 		i,
+		formatExtra(ld),
 	)
 }
+
+// formatExtra renders the Extra fields and Addendum messages attached to ld
+// via LogOf, as a suffix ready to be appended to a log line.
+func formatExtra(ld LogData) string {
+	var b strings.Builder
+	for k, v := range ld.Extra {
+		fmt.Fprintf(&b, " %s=%v", k, v)
+	}
+	for _, msg := range ld.Addendum {
+		fmt.Fprintf(&b, " (%s)", msg)
+	}
+	return b.String()
+}
@@ -0,0 +1,626 @@
+package middlelogger
+
+// This file is generated by hand following the combinatorial-dispatch
+// approach popularized by the httpsnoop package: for every optional
+// http.ResponseWriter interface that the underlying writer may implement
+// (http.Hijacker, http.Flusher, http.Pusher, http.CloseNotifier and
+// io.ReaderFrom) there is a concrete wrapper type that implements exactly
+// that combination, so that type assertions performed by downstream code
+// (or by the net/http package itself) keep working through the logging
+// middleware instead of silently losing access to the optional behavior.
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+type loggedRequest_H struct{ *loggedRequest }
+
+type loggedRequest_F struct{ *loggedRequest }
+
+type loggedRequest_P struct{ *loggedRequest }
+
+type loggedRequest_C struct{ *loggedRequest }
+
+type loggedRequest_R struct{ *loggedRequest }
+
+type loggedRequest_HF struct{ *loggedRequest }
+
+type loggedRequest_HP struct{ *loggedRequest }
+
+type loggedRequest_HC struct{ *loggedRequest }
+
+type loggedRequest_HR struct{ *loggedRequest }
+
+type loggedRequest_FP struct{ *loggedRequest }
+
+type loggedRequest_FC struct{ *loggedRequest }
+
+type loggedRequest_FR struct{ *loggedRequest }
+
+type loggedRequest_PC struct{ *loggedRequest }
+
+type loggedRequest_PR struct{ *loggedRequest }
+
+type loggedRequest_CR struct{ *loggedRequest }
+
+type loggedRequest_HFP struct{ *loggedRequest }
+
+type loggedRequest_HFC struct{ *loggedRequest }
+
+type loggedRequest_HFR struct{ *loggedRequest }
+
+type loggedRequest_HPC struct{ *loggedRequest }
+
+type loggedRequest_HPR struct{ *loggedRequest }
+
+type loggedRequest_HCR struct{ *loggedRequest }
+
+type loggedRequest_FPC struct{ *loggedRequest }
+
+type loggedRequest_FPR struct{ *loggedRequest }
+
+type loggedRequest_FCR struct{ *loggedRequest }
+
+type loggedRequest_PCR struct{ *loggedRequest }
+
+type loggedRequest_HFPC struct{ *loggedRequest }
+
+type loggedRequest_HFPR struct{ *loggedRequest }
+
+type loggedRequest_HFCR struct{ *loggedRequest }
+
+type loggedRequest_HPCR struct{ *loggedRequest }
+
+type loggedRequest_FPCR struct{ *loggedRequest }
+
+type loggedRequest_HFPCR struct{ *loggedRequest }
+
+func (lr *loggedRequest_H) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_F) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_P) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_C) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_R) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HF) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HF) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HP) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HP) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HC) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_FP) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FP) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_FC) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_FR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_PC) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_PC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_PR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_PR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_CR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_CR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HFP) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFP) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFP) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HFC) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFC) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HFR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HPC) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HPC) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HPC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HPR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HPR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HPR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HCR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_FPC) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FPC) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_FPC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_FPR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FPR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_FPR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_FCR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_FCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_PCR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_PCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_PCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HFPC) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFPC) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFPC) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HFPC) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HFPR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFPR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFPR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HFPR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HFCR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFCR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HFCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HPCR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HPCR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HPCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HPCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_FPCR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_FPCR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_FPCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_FPCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+func (lr *loggedRequest_HFPCR) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lr.w.(http.Hijacker).Hijack()
+	if err == nil {
+		lr.markHijacked()
+	}
+	return conn, rw, err
+}
+
+func (lr *loggedRequest_HFPCR) Flush() {
+	lr.w.(http.Flusher).Flush()
+	lr.recordFlush()
+}
+
+func (lr *loggedRequest_HFPCR) Push(target string, opts *http.PushOptions) error {
+	return lr.w.(http.Pusher).Push(target, opts)
+}
+
+func (lr *loggedRequest_HFPCR) CloseNotify() <-chan bool {
+	return lr.w.(http.CloseNotifier).CloseNotify()
+}
+
+func (lr *loggedRequest_HFPCR) ReadFrom(src io.Reader) (int64, error) {
+	n, err := lr.w.(io.ReaderFrom).ReadFrom(src)
+	lr.mtx.Lock()
+	lr.bytesWritten += n
+	lr.mtx.Unlock()
+	return n, err
+}
+
+// wrapResponseWriter returns an http.ResponseWriter that wraps lr and
+// implements exactly the optional interfaces (http.Hijacker, http.Flusher,
+// http.Pusher, http.CloseNotifier, io.ReaderFrom) that w itself implements,
+// so that a downstream handler type-asserting w for one of these keeps
+// seeing it through the logging middleware.
+func wrapResponseWriter(lr *loggedRequest, w http.ResponseWriter) http.ResponseWriter {
+	_, hj := w.(http.Hijacker)
+	_, fl := w.(http.Flusher)
+	_, pu := w.(http.Pusher)
+	_, cn := w.(http.CloseNotifier)
+	_, rf := w.(io.ReaderFrom)
+
+	switch {
+	case !hj && !fl && !pu && !cn && !rf:
+		return lr
+	case hj && !fl && !pu && !cn && !rf:
+		return &loggedRequest_H{lr}
+	case !hj && fl && !pu && !cn && !rf:
+		return &loggedRequest_F{lr}
+	case !hj && !fl && pu && !cn && !rf:
+		return &loggedRequest_P{lr}
+	case !hj && !fl && !pu && cn && !rf:
+		return &loggedRequest_C{lr}
+	case !hj && !fl && !pu && !cn && rf:
+		return &loggedRequest_R{lr}
+	case hj && fl && !pu && !cn && !rf:
+		return &loggedRequest_HF{lr}
+	case hj && !fl && pu && !cn && !rf:
+		return &loggedRequest_HP{lr}
+	case hj && !fl && !pu && cn && !rf:
+		return &loggedRequest_HC{lr}
+	case hj && !fl && !pu && !cn && rf:
+		return &loggedRequest_HR{lr}
+	case !hj && fl && pu && !cn && !rf:
+		return &loggedRequest_FP{lr}
+	case !hj && fl && !pu && cn && !rf:
+		return &loggedRequest_FC{lr}
+	case !hj && fl && !pu && !cn && rf:
+		return &loggedRequest_FR{lr}
+	case !hj && !fl && pu && cn && !rf:
+		return &loggedRequest_PC{lr}
+	case !hj && !fl && pu && !cn && rf:
+		return &loggedRequest_PR{lr}
+	case !hj && !fl && !pu && cn && rf:
+		return &loggedRequest_CR{lr}
+	case hj && fl && pu && !cn && !rf:
+		return &loggedRequest_HFP{lr}
+	case hj && fl && !pu && cn && !rf:
+		return &loggedRequest_HFC{lr}
+	case hj && fl && !pu && !cn && rf:
+		return &loggedRequest_HFR{lr}
+	case hj && !fl && pu && cn && !rf:
+		return &loggedRequest_HPC{lr}
+	case hj && !fl && pu && !cn && rf:
+		return &loggedRequest_HPR{lr}
+	case hj && !fl && !pu && cn && rf:
+		return &loggedRequest_HCR{lr}
+	case !hj && fl && pu && cn && !rf:
+		return &loggedRequest_FPC{lr}
+	case !hj && fl && pu && !cn && rf:
+		return &loggedRequest_FPR{lr}
+	case !hj && fl && !pu && cn && rf:
+		return &loggedRequest_FCR{lr}
+	case !hj && !fl && pu && cn && rf:
+		return &loggedRequest_PCR{lr}
+	case hj && fl && pu && cn && !rf:
+		return &loggedRequest_HFPC{lr}
+	case hj && fl && pu && !cn && rf:
+		return &loggedRequest_HFPR{lr}
+	case hj && fl && !pu && cn && rf:
+		return &loggedRequest_HFCR{lr}
+	case hj && !fl && pu && cn && rf:
+		return &loggedRequest_HPCR{lr}
+	case !hj && fl && pu && cn && rf:
+		return &loggedRequest_FPCR{lr}
+	case hj && fl && pu && cn && rf:
+		return &loggedRequest_HFPCR{lr}
+	}
+
+	// unreachable: the switch above is exhaustive over all 2^5 combinations.
+	return lr
+}
@@ -0,0 +1,152 @@
+package middlelogger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fullResponseWriter implements http.ResponseWriter plus every optional
+// interface that wrapResponseWriter knows how to preserve.
+type fullResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (fullResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return nil, nil, nil }
+func (fullResponseWriter) Flush()                                       {}
+func (fullResponseWriter) Push(string, *http.PushOptions) error         { return nil }
+func (fullResponseWriter) CloseNotify() <-chan bool                     { return nil }
+func (fullResponseWriter) ReadFrom(io.Reader) (int64, error)            { return 0, nil }
+
+// bareResponseWriter implements only the base http.ResponseWriter methods,
+// unlike httptest.NewRecorder() which already implements http.Flusher.
+type bareResponseWriter struct {
+	rec *httptest.ResponseRecorder
+}
+
+func (w bareResponseWriter) Header() http.Header         { return w.rec.Header() }
+func (w bareResponseWriter) Write(b []byte) (int, error) { return w.rec.Write(b) }
+func (w bareResponseWriter) WriteHeader(code int)        { w.rec.WriteHeader(code) }
+
+// TestWrapResponseWriterPreservesInterfaces tests that wrapResponseWriter
+// returns a wrapper that implements exactly the optional interfaces that the
+// underlying ResponseWriter implements, neither more nor less.
+func TestWrapResponseWriterPreservesInterfaces(t *testing.T) {
+	base := &loggedRequest{w: httptest.NewRecorder()}
+
+	full := wrapResponseWriter(base, fullResponseWriter{httptest.NewRecorder()})
+	if _, ok := full.(http.Hijacker); !ok {
+		t.Fatal("wrapped writer should implement http.Hijacker")
+	}
+	if _, ok := full.(http.Flusher); !ok {
+		t.Fatal("wrapped writer should implement http.Flusher")
+	}
+	if _, ok := full.(http.Pusher); !ok {
+		t.Fatal("wrapped writer should implement http.Pusher")
+	}
+	if _, ok := full.(http.CloseNotifier); !ok {
+		t.Fatal("wrapped writer should implement http.CloseNotifier")
+	}
+	if _, ok := full.(io.ReaderFrom); !ok {
+		t.Fatal("wrapped writer should implement io.ReaderFrom")
+	}
+
+	bare := wrapResponseWriter(base, bareResponseWriter{rec: httptest.NewRecorder()})
+	if _, ok := bare.(http.Hijacker); ok {
+		t.Fatal("wrapped writer should not fabricate http.Hijacker")
+	}
+	if _, ok := bare.(http.Flusher); ok {
+		t.Fatal("wrapped writer should not fabricate http.Flusher")
+	}
+	if _, ok := bare.(http.Pusher); ok {
+		t.Fatal("wrapped writer should not fabricate http.Pusher")
+	}
+	if _, ok := bare.(http.CloseNotifier); ok {
+		t.Fatal("wrapped writer should not fabricate http.CloseNotifier")
+	}
+	if _, ok := bare.(io.ReaderFrom); ok {
+		t.Fatal("wrapped writer should not fabricate io.ReaderFrom")
+	}
+}
+
+// hijackRecorder is an httptest.ResponseRecorder that also implements
+// http.Hijacker, since httptest.NewRecorder() does not.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	client, _ := net.Pipe()
+	return client, bufio.NewReadWriter(bufio.NewReader(client), bufio.NewWriter(client)), nil
+}
+
+// TestLogsHijack tests that hijacking the connection through the logging
+// middleware is recorded on the resulting LogData.
+func TestLogsHijack(t *testing.T) {
+	logg := &mockLogger{}
+	middle := LoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("response writer should implement http.Hijacker")
+		}
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			t.Fatalf("unexpected hijack error: %v", err)
+		}
+		conn.Close()
+	}), logg)
+
+	r := httptest.NewRequest("", "/", nil)
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	middle.ServeHTTP(w, r)
+
+	if !w.hijacked {
+		t.Fatal("underlying response writer was not hijacked")
+	}
+	if len(logg.reqs) != 1 {
+		t.Fatalf("unexpected nb of logged requests. want=1 got=%d", len(logg.reqs))
+	}
+	if !logg.reqs[0].Hijacked {
+		t.Fatal("logged request should be marked as hijacked")
+	}
+}
+
+// TestPanicAfterHijackDoesNotWriteResponse tests that a handler panicking
+// after hijacking the connection does not trigger the default error
+// response on the now-unowned writer, and that the panic is still logged
+// with Hijacked set to true.
+func TestPanicAfterHijackDoesNotWriteResponse(t *testing.T) {
+	logg := &mockLogger{}
+	middle := LoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("response writer should implement http.Hijacker")
+		}
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			t.Fatalf("unexpected hijack error: %v", err)
+		}
+		conn.Close()
+		panic("boo!")
+	}), logg)
+
+	r := httptest.NewRequest("", "/", nil)
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	middle.ServeHTTP(w, r)
+
+	if w.Code != 200 || w.Body.Len() != 0 {
+		t.Fatalf("hijacked writer should not receive the default error response: code=%d body=%q",
+			w.Code, w.Body.String())
+	}
+	if len(logg.panics) != 1 {
+		t.Fatalf("unexpected nb of logged panics. want=1 got=%d", len(logg.panics))
+	}
+	if !logg.panics[0].Hijacked {
+		t.Fatal("logged panic should be marked as hijacked")
+	}
+}